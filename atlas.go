@@ -0,0 +1,52 @@
+package main
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+//
+// ----------------------------------------------------------------------
+// IMAGE ATLAS
+// ----------------------------------------------------------------------
+//
+// Every simple, single-frame sprite (the door, potions, weapon pickups)
+// goes through one atlas keyed by ImageID, so we decode and upload each
+// PNG exactly once no matter how many places reference it. Animated actors
+// (the player, NPCs) go through SpriteSheet instead, since they need more
+// than one frame per sprite.
+//
+
+// ImageID keys a lookup into the shared image atlas.
+type ImageID int
+
+const (
+	ImgDoorClosed ImageID = iota
+	ImgFlaskRed
+	ImgFlaskBlue
+	ImgFlaskYellow
+	ImgFlaskGreen
+	ImgWeaponBomb
+	ImgWeaponChopper
+	ImgWeaponDaggerSilver
+	ImgWeaponDaggerSmall
+)
+
+// imageSource pairs each ImageID with its embedded PNG bytes.
+var imageSource = map[ImageID][]byte{
+	ImgDoorClosed:         doorClosedPNG,
+	ImgFlaskRed:           flaskRedPNG,
+	ImgFlaskBlue:          flaskBluePNG,
+	ImgFlaskYellow:        flaskYellowPNG,
+	ImgFlaskGreen:         flaskGreenPNG,
+	ImgWeaponBomb:         weaponBombPNG,
+	ImgWeaponChopper:      weaponChopperPNG,
+	ImgWeaponDaggerSilver: weaponDaggerSilverPNG,
+	ImgWeaponDaggerSmall:  weaponDaggerSmallPNG,
+}
+
+// loadImageAtlas decodes and uploads every known static sprite exactly once.
+func loadImageAtlas() map[ImageID]*ebiten.Image {
+	atlas := make(map[ImageID]*ebiten.Image, len(imageSource))
+	for id, png := range imageSource {
+		atlas[id] = loadSprite(png)
+	}
+	return atlas
+}
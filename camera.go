@@ -0,0 +1,111 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+//
+// ----------------------------------------------------------------------
+// CAMERA
+// ----------------------------------------------------------------------
+//
+// The viewport is fixed at viewportWidth x viewportHeight regardless of
+// how big the current map is. Camera tracks where in map-pixel space the
+// top-left of that viewport sits, plus a Zoom factor, and eases both
+// toward their targets each tick instead of snapping.
+//
+
+const (
+	viewportWidth  = 640
+	viewportHeight = 480
+
+	camEase = 0.1 // how quickly the camera/zoom close the gap to their targets each tick
+
+	zoomStep = 0.25
+	minZoom  = 0.5
+	maxZoom  = 3.0
+)
+
+// Camera is the current view into the map, in map-pixel coordinates.
+type Camera struct {
+	X, Y float64
+	Zoom float64
+}
+
+// clampF clamps v to [lo, hi].
+func clampF(v, lo, hi float64) float64 {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+// viewSize returns the visible map-pixel width/height at the camera's
+// current zoom.
+func (g *Game) viewSize() (float64, float64) {
+	return viewportWidth / g.cam.Zoom, viewportHeight / g.cam.Zoom
+}
+
+// worldToScreen applies the camera's pan and zoom to a GeoM that has
+// already been positioned in map-pixel space.
+func (g *Game) worldToScreen(op *ebiten.DrawImageOptions) {
+	op.GeoM.Translate(-g.cam.X, -g.cam.Y)
+	op.GeoM.Scale(g.cam.Zoom, g.cam.Zoom)
+}
+
+// onScreen reports whether a map-pixel-space AABB overlaps the current
+// viewport, so Draw* can skip anything that's fully offscreen.
+func (g *Game) onScreen(x, y, w, h float64) bool {
+	viewW, viewH := g.viewSize()
+	return x+w >= g.cam.X && x <= g.cam.X+viewW && y+h >= g.cam.Y && y <= g.cam.Y+viewH
+}
+
+// updateCamera handles zoom input, then eases the camera toward the
+// player and clamps it to the map bounds (centering instead, on either
+// axis where the map is smaller than the viewport).
+func (g *Game) updateCamera() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEqual) || inpututil.IsKeyJustPressed(ebiten.KeyKPAdd) {
+		g.camScaleTo += zoomStep
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyMinus) || inpututil.IsKeyJustPressed(ebiten.KeyKPSubtract) {
+		g.camScaleTo -= zoomStep
+	}
+	g.camScaleTo = clampF(g.camScaleTo, minZoom, maxZoom)
+	g.cam.Zoom += (g.camScaleTo - g.cam.Zoom) * camEase
+
+	viewW, viewH := g.viewSize()
+
+	targetX := g.player.X + float64(tileSize)/2 - viewW/2
+	targetY := g.player.Y + float64(tileSize)/2 - viewH/2
+	g.cam.X += (targetX - g.cam.X) * camEase
+	g.cam.Y += (targetY - g.cam.Y) * camEase
+
+	tm := g.currentMap()
+	if tm == nil {
+		return
+	}
+
+	mapW := float64(tm.Width * tileSize)
+	mapH := float64(tm.Height * tileSize)
+
+	if mapW <= viewW {
+		g.cam.X = (mapW - viewW) / 2
+	} else {
+		g.cam.X = clampF(g.cam.X, 0, mapW-viewW)
+	}
+	if mapH <= viewH {
+		g.cam.Y = (mapH - viewH) / 2
+	} else {
+		g.cam.Y = clampF(g.cam.Y, 0, mapH-viewH)
+	}
+}
+
+// screenToWorld converts a screen-space point (e.g. the mouse cursor)
+// into map-pixel space, undoing the camera's pan and zoom.
+func (g *Game) screenToWorld(sx, sy float64) (float64, float64) {
+	return g.cam.X + sx/g.cam.Zoom, g.cam.Y + sy/g.cam.Zoom
+}
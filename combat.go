@@ -0,0 +1,205 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// gibColor is the dark splat left behind where an NPC died.
+var gibColor = color.RGBA{R: 0x5a, G: 0x0a, B: 0x0a, A: 0xc0}
+
+//
+// ----------------------------------------------------------------------
+// RANGED COMBAT
+// ----------------------------------------------------------------------
+//
+// Weapons are what used to be instant-death bad items: picking one up arms
+// the player with it, and firing spawns a Projectile aimed at the mouse (or
+// straight in the player's facing direction if Space is used instead).
+//
+
+// weaponDef is the fire behavior for one equipped weapon.
+type weaponDef struct {
+	Damage int
+	Speed  float64
+	TTL    int
+	Img    *ebiten.Image // projectile sprite; reuses the pickup's own image
+}
+
+// newWeaponDefs builds the weaponDef table from the already-loaded bad item
+// images, in the same order as g.badItemKinds.
+func newWeaponDefs(badItemImages []*ebiten.Image) map[ItemKind]weaponDef {
+	defs := map[ItemKind]weaponDef{}
+	if len(badItemImages) > 0 {
+		defs[ItemKindWeaponBomb] = weaponDef{Damage: 3, Speed: 3.0, TTL: 90, Img: badItemImages[0]}
+	}
+	if len(badItemImages) > 1 {
+		defs[ItemKindWeaponChopper] = weaponDef{Damage: 2, Speed: 4.5, TTL: 60, Img: badItemImages[1]}
+	}
+	if len(badItemImages) > 2 {
+		defs[ItemKindWeaponDaggerSilver] = weaponDef{Damage: 2, Speed: 5.0, TTL: 50, Img: badItemImages[2]}
+	}
+	if len(badItemImages) > 3 {
+		defs[ItemKindWeaponDaggerSmall] = weaponDef{Damage: 1, Speed: 5.5, TTL: 45, Img: badItemImages[3]}
+	}
+	return defs
+}
+
+// dirVector turns a Player.Dir string into a unit aim vector, used when
+// firing with Space instead of the mouse.
+func dirVector(dir string) (float64, float64) {
+	switch dir {
+	case "up":
+		return 0, -1
+	case "down":
+		return 0, 1
+	case "left":
+		return -1, 0
+	case "right":
+		return 1, 0
+	default:
+		return 0, 1
+	}
+}
+
+// updateCombat fires the player's equipped weapon on a mouse click or
+// Space, aiming at the cursor (or the player's facing direction for Space).
+func (g *Game) updateCombat() {
+	clicked := inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft)
+	spaced := inpututil.IsKeyJustPressed(ebiten.KeySpace)
+	if !clicked && !spaced {
+		return
+	}
+	if g.player.Weapon == ItemKindNone || g.player.Ammo <= 0 {
+		return
+	}
+	def, ok := g.weaponDefs[g.player.Weapon]
+	if !ok {
+		return
+	}
+
+	px := g.player.X + float64(tileSize)/2
+	py := g.player.Y + float64(tileSize)/2
+
+	var aimX, aimY float64
+	if clicked {
+		mx, my := ebiten.CursorPosition()
+		aimX, aimY = g.screenToWorld(float64(mx), float64(my))
+	} else {
+		dx, dy := dirVector(g.player.Dir)
+		aimX, aimY = px+dx, py+dy
+	}
+
+	angle := math.Atan2(aimY-py, aimX-px)
+
+	g.projectiles = append(g.projectiles, Projectile{
+		X:      px,
+		Y:      py,
+		VX:     math.Cos(angle) * def.Speed,
+		VY:     math.Sin(angle) * def.Speed,
+		Damage: def.Damage,
+		TTL:    def.TTL,
+		Img:    def.Img,
+	})
+
+	g.player.Ammo--
+	if g.player.Ammo <= 0 {
+		g.player.Weapon = ItemKindNone
+	}
+
+	g.sound.Play(sfxShot)
+}
+
+// updateProjectiles advances every in-flight shot, removing it on a wall
+// hit, TTL expiry, or a hit against an NPC (which takes Damage and gibs at
+// zero Health).
+func (g *Game) updateProjectiles() {
+	tm := g.currentMap()
+
+	alive := g.projectiles[:0]
+	for _, p := range g.projectiles {
+		p.X += p.VX
+		p.Y += p.VY
+		p.TTL--
+
+		if p.TTL <= 0 {
+			continue
+		}
+		if tm != nil && isWallAtPixel(tm, p.X, p.Y, 1, 1) {
+			continue
+		}
+		if g.hitNPC(p) {
+			continue
+		}
+
+		alive = append(alive, p)
+	}
+	g.projectiles = alive
+
+	g.updateGibs()
+}
+
+// hitNPC damages the first living NPC within range of the projectile, and
+// gibs it if that brings its Health to zero. Reports whether it hit anyone.
+func (g *Game) hitNPC(p Projectile) bool {
+	const hitRadius2 = float64(tileSize) * float64(tileSize) * 0.5
+
+	for i := range g.npcs {
+		npc := &g.npcs[i]
+		if dist2(p.X, p.Y, npc.X+float64(tileSize)/2, npc.Y+float64(tileSize)/2) >= hitRadius2 {
+			continue
+		}
+
+		npc.Health -= p.Damage
+		g.sound.Play(sfxHit)
+
+		if npc.Health <= 0 {
+			g.gibs = append(g.gibs, Gib{X: npc.X, Y: npc.Y, TTL: gibTTL})
+			g.npcs = append(g.npcs[:i], g.npcs[i+1:]...)
+		}
+		return true
+	}
+	return false
+}
+
+const gibTTL = 30 // ticks a gib splat stays on screen
+
+// updateGibs ages out expired gib splats.
+func (g *Game) updateGibs() {
+	alive := g.gibs[:0]
+	for _, gib := range g.gibs {
+		gib.TTL--
+		if gib.TTL > 0 {
+			alive = append(alive, gib)
+		}
+	}
+	g.gibs = alive
+}
+
+func (g *Game) drawProjectiles(screen *ebiten.Image) {
+	for _, p := range g.projectiles {
+		if p.Img == nil || !g.onScreen(p.X-float64(tileSize)/2, p.Y-float64(tileSize)/2, tileSize, tileSize) {
+			continue
+		}
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(p.X-float64(tileSize)/2, p.Y-float64(tileSize)/2)
+		g.worldToScreen(op)
+		screen.DrawImage(p.Img, op)
+	}
+}
+
+func (g *Game) drawGibs(screen *ebiten.Image) {
+	for _, gib := range g.gibs {
+		if !g.onScreen(gib.X, gib.Y, tileSize, tileSize) {
+			continue
+		}
+		x := (gib.X + 4 - g.cam.X) * g.cam.Zoom
+		y := (gib.Y + 4 - g.cam.Y) * g.cam.Zoom
+		size := (float64(tileSize) - 8) * g.cam.Zoom
+		ebitenutil.DrawRect(screen, x, y, size, size, gibColor)
+	}
+}
@@ -0,0 +1,250 @@
+package main
+
+import "math/rand"
+
+//
+// ----------------------------------------------------------------------
+// PROCEDURAL DUNGEON GENERATOR
+// ----------------------------------------------------------------------
+//
+// generateDungeon builds a *TileMap the same way parseTMX does, but from a
+// BSP split instead of a hand-authored TMX file: recursively carve the grid
+// into sub-rectangles down to dungeonMinRoomSize, drop one room per leaf,
+// then connect sibling rooms with L-shaped corridors. Everything else stays
+// a wall tile.
+//
+
+const (
+	// smallest side a BSP leaf is allowed to have; rooms are carved inside
+	// it with a 1-tile margin, so a room side can be as small as 2
+	dungeonMinRoomSize = 4
+)
+
+// bspLeaf is one node of the binary space partition used to lay out rooms.
+type bspLeaf struct {
+	X, Y, W, H  int
+	Left, Right *bspLeaf
+	Room        TileRect // only set on leaves, once carved
+}
+
+// dungeonGIDs picks the floor/wall GIDs for the procedural generator off
+// the real level1.tmx map instead of guessing at raw tileset indices: the
+// most common tile in its "floor" layer is a known-good floor GID, and
+// the most common tile in its "walls" layer (already solid, since
+// parseTMX marks every walls-layer GID) is a known-good wall GID.
+func dungeonGIDs() (floorGID, wallGID int) {
+	tm := parseTMX(level1TMX)
+
+	if layer := tm.layer("floor"); layer != nil {
+		floorGID = mostCommonGID(layer.Tiles)
+	}
+	if layer := tm.layer("walls"); layer != nil {
+		wallGID = mostCommonGID(layer.Tiles)
+	}
+	if wallGID == 0 {
+		for gid := range tm.WallGID {
+			wallGID = gid
+			break
+		}
+	}
+
+	return floorGID, wallGID
+}
+
+// mostCommonGID returns the most frequently occurring nonzero tile GID
+// in a layer's tile grid, or 0 if the layer is empty.
+func mostCommonGID(tiles [][]int) int {
+	counts := make(map[int]int)
+	for _, row := range tiles {
+		for _, gid := range row {
+			if gid > 0 {
+				counts[gid]++
+			}
+		}
+	}
+
+	best, bestCount := 0, 0
+	for gid, count := range counts {
+		if count > bestCount {
+			best, bestCount = gid, count
+		}
+	}
+	return best
+}
+
+// generateDungeon procedurally builds a WxH dungeon as an alternative to
+// loading a TMX map.
+func generateDungeon(width, height int) *TileMap {
+	floorGID, wallGID := dungeonGIDs()
+
+	floor := TileLayer{Name: "floor", Tiles: make([][]int, height)}
+	walls := TileLayer{Name: "walls", Tiles: make([][]int, height)}
+	for y := 0; y < height; y++ {
+		floor.Tiles[y] = make([]int, width)
+		walls.Tiles[y] = make([]int, width)
+		for x := 0; x < width; x++ {
+			walls.Tiles[y][x] = wallGID
+		}
+	}
+
+	root := &bspLeaf{X: 0, Y: 0, W: width, H: height}
+	splitLeaf(root)
+
+	var rooms []TileRect
+	carveLeaf(root, &floor, &walls, &rooms, floorGID)
+	connectLeaf(root, &floor, &walls, floorGID)
+
+	tm := &TileMap{
+		Width:   width,
+		Height:  height,
+		Layers:  []TileLayer{floor, walls},
+		WallGID: map[int]bool{wallGID: true},
+	}
+
+	if len(rooms) > 0 {
+		tm.PlayerSpawn = centerRect(rooms[0])
+	}
+	if len(rooms) > 1 {
+		tm.DoorSpawn = centerRect(rooms[len(rooms)-1])
+	}
+	tm.ItemSpots = rooms
+
+	return tm
+}
+
+// splitLeaf recursively splits a leaf in two at a random point (picking
+// the longer axis, with a little randomness) until it's too small to
+// split again.
+func splitLeaf(leaf *bspLeaf) {
+	if leaf.W < dungeonMinRoomSize*2 && leaf.H < dungeonMinRoomSize*2 {
+		return
+	}
+
+	splitHoriz := rand.Intn(2) == 0
+	if float64(leaf.W)/float64(leaf.H) >= 1.25 {
+		splitHoriz = false
+	} else if float64(leaf.H)/float64(leaf.W) >= 1.25 {
+		splitHoriz = true
+	}
+	if splitHoriz && leaf.H < dungeonMinRoomSize*2 {
+		splitHoriz = false
+	}
+	if !splitHoriz && leaf.W < dungeonMinRoomSize*2 {
+		splitHoriz = true
+	}
+
+	if splitHoriz {
+		split := dungeonMinRoomSize + rand.Intn(leaf.H-dungeonMinRoomSize*2+1)
+		leaf.Left = &bspLeaf{X: leaf.X, Y: leaf.Y, W: leaf.W, H: split}
+		leaf.Right = &bspLeaf{X: leaf.X, Y: leaf.Y + split, W: leaf.W, H: leaf.H - split}
+	} else {
+		split := dungeonMinRoomSize + rand.Intn(leaf.W-dungeonMinRoomSize*2+1)
+		leaf.Left = &bspLeaf{X: leaf.X, Y: leaf.Y, W: split, H: leaf.H}
+		leaf.Right = &bspLeaf{X: leaf.X + split, Y: leaf.Y, W: leaf.W - split, H: leaf.H}
+	}
+
+	splitLeaf(leaf.Left)
+	splitLeaf(leaf.Right)
+}
+
+// carveLeaf walks to every leaf of the BSP tree and carves a room inside it,
+// leaving a 1-tile margin so rooms never touch their leaf's edge.
+func carveLeaf(leaf *bspLeaf, floor, walls *TileLayer, rooms *[]TileRect, floorGID int) {
+	if leaf == nil {
+		return
+	}
+	if leaf.Left != nil || leaf.Right != nil {
+		carveLeaf(leaf.Left, floor, walls, rooms, floorGID)
+		carveLeaf(leaf.Right, floor, walls, rooms, floorGID)
+		return
+	}
+
+	const margin = 1
+	maxW := leaf.W - margin*2
+	maxH := leaf.H - margin*2
+	if maxW < 2 {
+		maxW = 2
+	}
+	if maxH < 2 {
+		maxH = 2
+	}
+	roomW := 2 + rand.Intn(maxW-1)
+	roomH := 2 + rand.Intn(maxH-1)
+	roomX := leaf.X + margin + rand.Intn(leaf.W-roomW-margin*2+1)
+	roomY := leaf.Y + margin + rand.Intn(leaf.H-roomH-margin*2+1)
+
+	leaf.Room = TileRect{X: roomX, Y: roomY, W: roomW, H: roomH}
+	carveRect(floor, walls, leaf.Room, floorGID)
+	*rooms = append(*rooms, leaf.Room)
+}
+
+// connectLeaf walks the BSP tree bottom-up, joining each pair of sibling
+// subtrees with an L-shaped corridor between one of their rooms.
+func connectLeaf(leaf *bspLeaf, floor, walls *TileLayer, floorGID int) {
+	if leaf == nil || leaf.Left == nil || leaf.Right == nil {
+		return
+	}
+	connectLeaf(leaf.Left, floor, walls, floorGID)
+	connectLeaf(leaf.Right, floor, walls, floorGID)
+
+	a := leafRoom(leaf.Left)
+	b := leafRoom(leaf.Right)
+	carveCorridor(floor, walls, centerRect(a), centerRect(b), floorGID)
+}
+
+// leafRoom finds some carved room belonging to a leaf's subtree.
+func leafRoom(leaf *bspLeaf) TileRect {
+	if leaf.Left == nil && leaf.Right == nil {
+		return leaf.Room
+	}
+	if leaf.Left != nil {
+		return leafRoom(leaf.Left)
+	}
+	return leafRoom(leaf.Right)
+}
+
+// centerRect collapses a rect down to its 1x1 center tile.
+func centerRect(r TileRect) TileRect {
+	return TileRect{X: r.X + r.W/2, Y: r.Y + r.H/2, W: 1, H: 1}
+}
+
+// carveRect stamps floor GIDs and clears wall GIDs over a tile rectangle.
+func carveRect(floor, walls *TileLayer, r TileRect, floorGID int) {
+	for y := r.Y; y < r.Y+r.H; y++ {
+		if y < 0 || y >= len(floor.Tiles) {
+			continue
+		}
+		for x := r.X; x < r.X+r.W; x++ {
+			if x < 0 || x >= len(floor.Tiles[y]) {
+				continue
+			}
+			floor.Tiles[y][x] = floorGID
+			walls.Tiles[y][x] = 0
+		}
+	}
+}
+
+// carveCorridor joins two points with an L-shaped (random elbow) corridor.
+func carveCorridor(floor, walls *TileLayer, a, b TileRect, floorGID int) {
+	if rand.Intn(2) == 0 {
+		carveRect(floor, walls, hLine(a.X, b.X, a.Y), floorGID)
+		carveRect(floor, walls, vLine(a.Y, b.Y, b.X), floorGID)
+	} else {
+		carveRect(floor, walls, vLine(a.Y, b.Y, a.X), floorGID)
+		carveRect(floor, walls, hLine(a.X, b.X, b.Y), floorGID)
+	}
+}
+
+func hLine(x1, x2, y int) TileRect {
+	if x2 < x1 {
+		x1, x2 = x2, x1
+	}
+	return TileRect{X: x1, Y: y, W: x2 - x1 + 1, H: 1}
+}
+
+func vLine(y1, y2, x int) TileRect {
+	if y2 < y1 {
+		y1, y2 = y2, y1
+	}
+	return TileRect{X: x, Y: y1, W: 1, H: y2 - y1 + 1}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+//
+// ----------------------------------------------------------------------
+// LIGHTING / FOG OF WAR
+// ----------------------------------------------------------------------
+//
+// Every tick we redraw an offscreen, viewport-sized lightImage: a dark
+// fill with every active Light additively stamped onto it, then
+// composite that over the scene with CompositeModeMultiply so only the
+// lit radius around the player, the door, and wall torches stays bright.
+//
+
+const (
+	lightSpriteRadius = 128 // reference radius, in px, of the baked gradient sprite
+
+	lightPlayerRadius      = 96  // player's own torch
+	lightPlayerBoostRadius = 176 // player radius while a torch pickup is active
+	lightDoorRadius        = 80  // the level exit glows once it's unlocked
+	lightWallTorchRadius   = 64  // TorchSpots placed on the TMX object layer
+
+	torchBoostDurationTicks = 600 // ~10s at 60 TPS, mirrors repelDurationTicks
+)
+
+// Light is one radial glow stamped onto the lighting overlay, in
+// map-pixel space.
+type Light struct {
+	X, Y   float64
+	Radius float64
+}
+
+// newLightSprite bakes a single white-to-transparent radial gradient,
+// reused (scaled per-light) for every light source instead of building
+// one per draw call.
+func newLightSprite() *ebiten.Image {
+	size := lightSpriteRadius * 2
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	cx, cy := float64(lightSpriteRadius), float64(lightSpriteRadius)
+
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			d := math.Hypot(float64(x)-cx, float64(y)-cy) / float64(lightSpriteRadius)
+			a := 1 - d
+			if a < 0 {
+				a = 0
+			}
+			v := uint8(a * 255)
+			img.Set(x, y, color.RGBA{R: v, G: v, B: v, A: v})
+		}
+	}
+
+	return ebiten.NewImageFromImage(img)
+}
+
+// activeLights collects every light source currently lit: the player's
+// torch (bigger while a torch pickup is active), the door once it's
+// unlocked, and every wall torch placed on the map.
+func (g *Game) activeLights() []Light {
+	playerRadius := lightPlayerRadius
+	if g.player.TorchTicks > 0 {
+		playerRadius = lightPlayerBoostRadius
+	}
+
+	lights := make([]Light, 0, len(g.worldTorches)+2)
+	lights = append(lights, Light{
+		X:      g.player.X + float64(tileSize)/2,
+		Y:      g.player.Y + float64(tileSize)/2,
+		Radius: float64(playerRadius),
+	})
+
+	if g.doorVisible {
+		lights = append(lights, Light{
+			X:      g.doorX + float64(tileSize)/2,
+			Y:      g.doorY + float64(tileSize)/2,
+			Radius: lightDoorRadius,
+		})
+	}
+
+	lights = append(lights, g.worldTorches...)
+	return lights
+}
+
+// updateLighting decrements the player's torch-boost timer and handles
+// the full-bright debug toggle.
+func (g *Game) updateLighting() {
+	if g.player.TorchTicks > 0 {
+		g.player.TorchTicks--
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyL) {
+		g.fullBrightMode = !g.fullBrightMode
+	}
+}
+
+// drawLighting redraws the lighting overlay for every visible light
+// source, then multiplies it over the already-drawn scene. A no-op in
+// fullBrightMode.
+func (g *Game) drawLighting(screen *ebiten.Image) {
+	if g.fullBrightMode || g.lightImage == nil {
+		return
+	}
+
+	floor := uint8(clampF(g.minLevelColorScale, 0, 1) * 255)
+	g.lightImage.Fill(color.RGBA{R: floor, G: floor, B: floor, A: 0xff})
+
+	for _, l := range g.activeLights() {
+		if !g.onScreen(l.X-l.Radius, l.Y-l.Radius, l.Radius*2, l.Radius*2) {
+			continue
+		}
+
+		scale := l.Radius * g.cam.Zoom / lightSpriteRadius
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(-lightSpriteRadius, -lightSpriteRadius)
+		op.GeoM.Scale(scale, scale)
+		op.GeoM.Translate((l.X-g.cam.X)*g.cam.Zoom, (l.Y-g.cam.Y)*g.cam.Zoom)
+		op.CompositeMode = ebiten.CompositeModeLighter
+		g.lightImage.DrawImage(g.lightSprite, op)
+	}
+
+	// keep the player's own tile from ever going fully dark, even if
+	// minLevelColorScale is near zero
+	playerFloor := uint8(clampF(g.minPlayerColorScale, 0, 1) * 255)
+	px := (g.player.X - g.cam.X) * g.cam.Zoom
+	py := (g.player.Y - g.cam.Y) * g.cam.Zoom
+	size := float64(tileSize) * g.cam.Zoom
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(size/float64(lightSpriteRadius*2), size/float64(lightSpriteRadius*2))
+	op.GeoM.Translate(px, py)
+	op.ColorM.Scale(1, 1, 1, float64(playerFloor)/255)
+	op.CompositeMode = ebiten.CompositeModeLighter
+	g.lightImage.DrawImage(g.lightSprite, op)
+
+	overlay := &ebiten.DrawImageOptions{}
+	overlay.CompositeMode = ebiten.CompositeModeMultiply
+	screen.DrawImage(g.lightImage, overlay)
+}
@@ -3,9 +3,11 @@ package main
 import (
 	"bytes"
 	_ "embed"
+	"flag"
 	"fmt"
 	"image"
 	"log"
+	"math"
 	"math/rand"
 	"strconv"
 	"strings"
@@ -56,6 +58,9 @@ var flaskBluePNG []byte
 //go:embed assets/sprites/flask_big_yellow.png
 var flaskYellowPNG []byte
 
+//go:embed assets/sprites/flask_big_green.png
+var flaskGreenPNG []byte
+
 // bad items
 //
 //go:embed assets/sprites/weapon_bomb.png
@@ -109,10 +114,12 @@ const (
 	mapWidth  = 20
 	mapHeight = 20
 
-	screenWidth  = mapWidth * tileSize
-	screenHeight = mapHeight * tileSize
-
 	itemsNeeded = 9 // how many good items to unlock the door
+
+	repelDurationTicks = 600 // ~10s at 60 TPS: how long a yellow flask scares NPCs off
+
+	npcDefaultHealth  = 3 // NPC health; each hit subtracts the weapon's Damage, and it gibs at zero
+	weaponAmmoPerPick = 6 // ammo granted by picking up a weapon item
 )
 
 //
@@ -121,12 +128,108 @@ const (
 // ----------------------------------------------------------------------
 //
 
-// TileMap holds the tile IDs from Tiled
+// TileMap holds everything we pulled out of a Tiled export: the ordered
+// list of rendered layers, which global tile IDs are solid, and the
+// object-layer entries (player spawn, door, NPC patrol boxes, item spots).
 type TileMap struct {
-	Width   int
-	Height  int
-	Tiles   [][]int      // each entry is a global tile ID from TMX
-	WallGID map[int]bool // which tile IDs are walls (we leave this empty)
+	Width  int
+	Height int
+
+	Layers  []TileLayer  // drawn in TMX order, e.g. "floor", "walls", "decor"
+	WallGID map[int]bool // tile IDs that block movement
+
+	PlayerSpawn TileRect
+	DoorSpawn   TileRect
+	NPCPatrols  []TileRect
+	ItemSpots   []TileRect
+	TorchSpots  []TileRect // wall torches (TypeTorch decor objects, lit by lighting.go)
+}
+
+// TileLayer is one named <layer> worth of global tile IDs (0 = empty).
+type TileLayer struct {
+	Name  string
+	Tiles [][]int
+}
+
+// TileRect is an object-layer rectangle, in tile coordinates.
+type TileRect struct {
+	X, Y, W, H int
+}
+
+// layer looks up a named layer (e.g. "floor" or "walls"), or nil.
+func (tm *TileMap) layer(name string) *TileLayer {
+	for i := range tm.Layers {
+		if tm.Layers[i].Name == name {
+			return &tm.Layers[i]
+		}
+	}
+	return nil
+}
+
+// isWallTile reports whether any layer places a solid GID at (tx, ty).
+func (tm *TileMap) isWallTile(tx, ty int) bool {
+	for _, layer := range tm.Layers {
+		if ty < 0 || ty >= len(layer.Tiles) {
+			continue
+		}
+		row := layer.Tiles[ty]
+		if tx < 0 || tx >= len(row) {
+			continue
+		}
+		if tm.WallGID[row[tx]] {
+			return true
+		}
+	}
+	return false
+}
+
+// isFloor reports whether (x, y) is an in-bounds, walkable tile. Used to
+// keep spawns (items, NPCs, the player, the door) out of walls on both
+// hand-authored TMX maps and procedurally generated ones.
+func (tm *TileMap) isFloor(x, y int) bool {
+	if tm == nil || x < 0 || y < 0 || x >= tm.Width || y >= tm.Height {
+		return false
+	}
+	return !tm.isWallTile(x, y)
+}
+
+// findFloorTile looks for a random walkable tile, giving up and returning
+// the fallback coordinates if it never finds one within the try-cap.
+func findFloorTile(tm *TileMap, fallbackX, fallbackY int) (int, int) {
+	const maxTries = 500
+	for i := 0; i < maxTries; i++ {
+		x := rand.Intn(tm.Width)
+		y := rand.Intn(tm.Height)
+		if tm.isFloor(x, y) {
+			return x, y
+		}
+	}
+	return fallbackX, fallbackY
+}
+
+// randomTileInRect picks a uniformly random tile coordinate inside rect.
+func randomTileInRect(rect TileRect) (int, int) {
+	w, h := rect.W, rect.H
+	if w < 1 {
+		w = 1
+	}
+	if h < 1 {
+		h = 1
+	}
+	return rect.X + rand.Intn(w), rect.Y + rand.Intn(h)
+}
+
+// patrolPartner finds a floor tile within radius tiles of (tx, ty) to
+// patrol between, falling back to (tx, ty) itself if it can't find one.
+func patrolPartner(tm *TileMap, tx, ty, radius int) (int, int) {
+	for try := 0; try < 50; try++ {
+		cx := tx + rand.Intn(radius*2+1) - radius
+		cy := ty + rand.Intn(radius*2+1) - radius
+		if tm.isFloor(cx, cy) {
+			return cx, cy
+		}
+	}
+	return tx, ty
 }
 
 // Player is the controllable knight
@@ -137,23 +240,85 @@ type Player struct {
 	Frame     int
 	FrameTick int
 	Frames    map[string][]*ebiten.Image // animation frames per direction
+
+	RepelTicks int // while > 0, the player is holding an active repel item
+	TorchTicks int // while > 0, the player's torch light radius is boosted
+
+	Weapon ItemKind // currently equipped weapon, ItemKindNone if unarmed
+	Ammo   int
 }
 
+// ItemKind distinguishes items that behave differently from a plain
+// pickup, beyond just good/bad.
+type ItemKind int
+
+const (
+	ItemKindNone   ItemKind = iota // no weapon equipped
+	ItemKindPotion                 // plain good potion
+	ItemKindRepel                  // good item (yellow flask): scares NPCs off while held
+	ItemKindTorch                  // good item (green flask): boosts the player's light radius while held
+
+	// bad items are weapon pickups: grabbing one arms the player with it
+	ItemKindWeaponBomb
+	ItemKindWeaponChopper
+	ItemKindWeaponDaggerSilver
+	ItemKindWeaponDaggerSmall
+)
+
 // Item is either good (potion) or bad (weapon/bomb)
 type Item struct {
 	X, Y   float64
 	Bad    bool
 	Active bool
 	Img    *ebiten.Image
+	Kind   ItemKind
 }
 
-// NPC just walks back and forth (or around) in level 2
+// Behavior is an NPC's current movement mode.
+type Behavior int
+
+const (
+	BehaviorPatrol Behavior = iota // bounce back and forth inside its box
+	BehaviorSeek                   // head straight for the player
+	BehaviorFlee                   // head straight away from the player
+	BehaviorWander                 // pick a random direction
+)
+
+// NPC seeks or flees the player, or patrols/wanders when it isn't close
+// enough to care, in level 2.
 type NPC struct {
 	X, Y       float64
 	VX, VY     float64
 	MinX, MaxX float64
 	MinY, MaxY float64
-	Img        *ebiten.Image
+
+	Frames    []*ebiten.Image // walk-cycle frames, from this NPC's own spritesheet
+	Frame     int
+	FrameTick int
+
+	Behavior   Behavior
+	Speed      float64 // current per-tick speed, re-rolled between MinSpeed/MaxSpeed
+	MinSpeed   float64
+	MaxSpeed   float64
+	nextAction int // ticks until this NPC re-plans its behavior
+
+	Health int
+}
+
+// Gib is the brief splat effect left behind when an NPC's Health hits zero.
+type Gib struct {
+	X, Y float64
+	TTL  int
+}
+
+// Projectile is a fired shot travelling in a straight line until it hits a
+// wall, an NPC, or runs out of TTL.
+type Projectile struct {
+	X, Y   float64
+	VX, VY float64
+	Damage int
+	TTL    int
+	Img    *ebiten.Image
 }
 
 // Game holds all state
@@ -164,12 +329,18 @@ type Game struct {
 	map2 *TileMap
 
 	tileImages []*ebiten.Image
+	imageAtlas map[ImageID]*ebiten.Image
+
+	cam        Camera
+	camScaleTo float64 // Zoom target that cam.Zoom eases toward each tick
 
 	player Player
 
 	// items
 	goodItemImages []*ebiten.Image
+	goodItemKinds  []ItemKind // parallel to goodItemImages
 	badItemImages  []*ebiten.Image
+	badItemKinds   []ItemKind // parallel to badItemImages
 	goodItems      []Item
 	badItems       []Item
 
@@ -180,8 +351,23 @@ type Game struct {
 	doorVisible bool
 
 	// NPCs on level 2
-	npcImages []*ebiten.Image
-	npcs      []NPC
+	npcFrameSets [][]*ebiten.Image // one animated walk cycle per NPC type
+	npcs         []NPC
+	gibs         []Gib
+
+	// ranged combat
+	weaponDefs  map[ItemKind]weaponDef
+	projectiles []Projectile
+
+	sound *SoundPlayer
+
+	// lighting / fog of war
+	lightImage          *ebiten.Image
+	lightSprite         *ebiten.Image
+	worldTorches        []Light // static wall torches for the current level
+	minLevelColorScale  float64 // floor brightness so the map never goes pitch black
+	minPlayerColorScale float64 // floor brightness directly under the player
+	fullBrightMode      bool    // debug toggle (L key): skips the lighting pass
 
 	collected int
 	levelGoal int // 9
@@ -205,15 +391,6 @@ func loadSprite(pngBytes []byte) *ebiten.Image {
 	return ebiten.NewImageFromImage(img)
 }
 
-// load several PNGs at once
-func loadSprites(pngs ...[]byte) []*ebiten.Image {
-	res := make([]*ebiten.Image, 0, len(pngs))
-	for _, b := range pngs {
-		res = append(res, loadSprite(b))
-	}
-	return res
-}
-
 // distance squared between two points, used for collisions
 func dist2(x1, y1, x2, y2 float64) float64 {
 	dx := x1 - x2
@@ -221,6 +398,14 @@ func dist2(x1, y1, x2, y2 float64) float64 {
 	return dx*dx + dy*dy
 }
 
+// minMax returns (a, b) in ascending order.
+func minMax(a, b int) (int, int) {
+	if a > b {
+		return b, a
+	}
+	return a, b
+}
+
 //
 // ----------------------------------------------------------------------
 // LOAD TILESET
@@ -276,32 +461,115 @@ func extractIntAttr(text, key string) int {
 	return n
 }
 
-// parseTMX reads width/height and the CSV layer into TileMap.Tiles
-func parseTMX(data []byte) *TileMap {
-	text := string(data)
+// pull x="288.667" style attribute out of the TMX text. Object x/y/width/
+// height are floats in the TMX spec (Tiled writes fractional coordinates
+// for freely-placed objects), unlike the integer map/tileset attributes
+// extractIntAttr handles.
+func extractFloatAttr(text, key string) float64 {
+	i := strings.Index(text, key)
+	if i < 0 {
+		return 0
+	}
+	i += len(key)
+	j := strings.Index(text[i:], "\"")
+	if j < 0 {
+		return 0
+	}
+	n, _ := strconv.ParseFloat(text[i:i+j], 64)
+	return n
+}
 
-	tm := &TileMap{
-		WallGID: make(map[int]bool),
+// pull name="floor" style attribute out of the TMX text
+func extractStrAttr(text, key string) string {
+	i := strings.Index(text, key)
+	if i < 0 {
+		return ""
+	}
+	i += len(key)
+	j := strings.Index(text[i:], "\"")
+	if j < 0 {
+		return ""
 	}
+	return text[i : i+j]
+}
 
-	tm.Width = extractIntAttr(text, `width="`)
-	tm.Height = extractIntAttr(text, `height="`)
+// indexTag finds the next real "<tag" occurrence at or after `from`,
+// skipping longer tag names that merely start with it (e.g. "<tileset"
+// when looking for "<tile").
+func indexTag(text, tag string, from int) int {
+	open := "<" + tag
+	for {
+		i := strings.Index(text[from:], open)
+		if i < 0 {
+			return -1
+		}
+		idx := from + i
+		after := idx + len(open)
+		if after >= len(text) || strings.ContainsRune(" \t\r\n>/", rune(text[after])) {
+			return idx
+		}
+		from = idx + len(open)
+	}
+}
+
+// findBlocks returns the full "<tag ...> ... </tag>" text of every match.
+func findBlocks(text, tag string) []string {
+	closeTag := "</" + tag + ">"
+	var blocks []string
+	pos := 0
+	for {
+		start := indexTag(text, tag, pos)
+		if start < 0 {
+			break
+		}
+		end := strings.Index(text[start:], closeTag)
+		if end < 0 {
+			break
+		}
+		end = start + end + len(closeTag)
+		blocks = append(blocks, text[start:end])
+		pos = end
+	}
+	return blocks
+}
+
+// findSelfClosing returns the "<tag .../>" header of every self-closed match.
+func findSelfClosing(text, tag string) []string {
+	var blocks []string
+	pos := 0
+	for {
+		start := indexTag(text, tag, pos)
+		if start < 0 {
+			break
+		}
+		end := strings.Index(text[start:], ">")
+		if end < 0 {
+			break
+		}
+		end = start + end + 1
+		blocks = append(blocks, text[start:end])
+		pos = end
+	}
+	return blocks
+}
 
-	// find <data> ... </data>
-	dataStart := strings.Index(text, "<data")
+// parseLayerBlock turns one <layer name="floor">...<data>csv</data></layer>
+// block into a TileLayer.
+func parseLayerBlock(block string) TileLayer {
+	layer := TileLayer{Name: extractStrAttr(block, `name="`)}
+
+	dataStart := strings.Index(block, "<data")
 	if dataStart < 0 {
-		log.Fatal("TMX: no <data> tag")
+		return layer
 	}
-	dataStart = strings.Index(text[dataStart:], ">") + dataStart + 1
-	dataEnd := strings.Index(text[dataStart:], "</data>")
+	dataStart = strings.Index(block[dataStart:], ">") + dataStart + 1
+	dataEnd := strings.Index(block[dataStart:], "</data>")
 	if dataEnd < 0 {
-		log.Fatal("TMX: no </data> tag")
+		return layer
 	}
+	csv := strings.TrimSpace(block[dataStart : dataStart+dataEnd])
 
-	csv := strings.TrimSpace(text[dataStart : dataStart+dataEnd])
-
-	rows := strings.Split(csv, "\n")
-	for _, row := range rows {
+	for _, row := range strings.Split(csv, "\n") {
 		row = strings.TrimSpace(row)
 		if row == "" {
 			continue
@@ -317,54 +585,172 @@ func parseTMX(data []byte) *TileMap {
 			line = append(line, n)
 		}
 		if len(line) > 0 {
-			tm.Tiles = append(tm.Tiles, line)
+			layer.Tiles = append(layer.Tiles, line)
 		}
 	}
+	return layer
+}
 
-	// IMPORTANT: we leave WallGID empty so *nothing* is a wall for now.
-	// isWallAtPixel only blocks you from leaving the map.
-	return tm
+// tileHasSolidProperty reports whether a <tile id="N">...</tile> block
+// carries a <property name="solid" value="true"/>.
+func tileHasSolidProperty(tileBlock string) bool {
+	for _, prop := range findSelfClosing(tileBlock, "property") {
+		if extractStrAttr(prop, `name="`) == "solid" && extractStrAttr(prop, `value="`) == "true" {
+			return true
+		}
+	}
+	return false
 }
 
-// ----------------------------------------------------------------------
-// COLLISION
-// ----------------------------------------------------------------------
-//
-// isWallAtPixel checks if a pixel location is outside the map or hits
-// a tile whose ID is marked as a wall in tm.WallGID.
-func isWallAtPixel(tm *TileMap, px, py float64) bool {
-	if tm == nil || tm.Width <= 0 || tm.Height <= 0 || len(tm.Tiles) == 0 {
-		return false
+// parseTilesets reads every <tileset firstgid="N">...</tileset> block and
+// marks any tile carrying a "solid" property as a wall GID.
+func parseTilesets(text string, tm *TileMap) {
+	for _, tileset := range findBlocks(text, "tileset") {
+		firstgid := extractIntAttr(tileset, `firstgid="`)
+		if firstgid <= 0 {
+			continue
+		}
+		for _, tileBlock := range findBlocks(tileset, "tile") {
+			if !tileHasSolidProperty(tileBlock) {
+				continue
+			}
+			id := extractIntAttr(tileBlock, ` id="`)
+			tm.WallGID[firstgid+id] = true
+		}
+	}
+}
+
+// objectRect converts an <object .../> header's pixel x/y/width/height into
+// a tile-coordinate rect.
+func objectRect(header string) TileRect {
+	w := int(extractFloatAttr(header, `width="`)) / tileSize
+	h := int(extractFloatAttr(header, `height="`)) / tileSize
+	if w == 0 {
+		w = 1
+	}
+	if h == 0 {
+		h = 1
+	}
+	return TileRect{
+		X: int(extractFloatAttr(header, `x="`)) / tileSize,
+		Y: int(extractFloatAttr(header, `y="`)) / tileSize,
+		W: w,
+		H: h,
 	}
+}
 
-	// Convert pixel position to tile indices.
-	tx := int(px) / tileSize
-	ty := int(py) / tileSize
+// findObjectHeaders returns the opening-tag text of every <object> inside
+// an <objectgroup>, whether it's self-closed or has children.
+func findObjectHeaders(group string) []string {
+	var headers []string
+	pos := 0
+	for {
+		start := indexTag(group, "object", pos)
+		if start < 0 {
+			break
+		}
+		end := strings.Index(group[start:], ">")
+		if end < 0 {
+			break
+		}
+		end = start + end + 1
+		headers = append(headers, group[start:end])
+		pos = end
+	}
+	return headers
+}
 
-	// If out of bounds, treat as a wall.
-	if tx < 0 || ty < 0 || tx >= tm.Width || ty >= tm.Height {
-		return true
+// TypeTorch is the TMX object "type" attribute that marks a wall-torch
+// decor placement for lighting.go.
+const TypeTorch = "torch"
+
+// parseObjectGroups reads the object layer: player spawn, door location,
+// NPC patrol rects, hand-placed item spots, and wall torches.
+func parseObjectGroups(text string, tm *TileMap) {
+	for _, group := range findBlocks(text, "objectgroup") {
+		for _, header := range findObjectHeaders(group) {
+			name := strings.ToLower(extractStrAttr(header, `name="`))
+			typ := strings.ToLower(extractStrAttr(header, `type="`))
+			rect := objectRect(header)
+
+			switch {
+			case name == "spawn" || name == "player_spawn" || typ == "spawn":
+				tm.PlayerSpawn = rect
+			case name == "door" || typ == "door":
+				tm.DoorSpawn = rect
+			case strings.Contains(name, "patrol") || typ == "npc_patrol":
+				tm.NPCPatrols = append(tm.NPCPatrols, rect)
+			case strings.Contains(name, "item") || typ == "item":
+				tm.ItemSpots = append(tm.ItemSpots, rect)
+			case strings.Contains(name, "torch") || typ == TypeTorch:
+				tm.TorchSpots = append(tm.TorchSpots, rect)
+			}
+		}
 	}
+}
 
-	// Force the *outer ring* of tiles to be solid walls.
-	// This guarantees the player can NEVER walk off the visible map.
-	if tx == 0 || ty == 0 || tx == tm.Width-1 || ty == tm.Height-1 {
-		return true
+// parseTMX reads the tileset(s), every named layer, and the object layer
+// out of a Tiled export.
+func parseTMX(data []byte) *TileMap {
+	text := string(data)
+
+	tm := &TileMap{
+		WallGID: make(map[int]bool),
 	}
 
-	if ty < 0 || ty >= len(tm.Tiles) {
-		return false
+	tm.Width = extractIntAttr(text, `width="`)
+	tm.Height = extractIntAttr(text, `height="`)
+
+	parseTilesets(text, tm)
+
+	for _, block := range findBlocks(text, "layer") {
+		layer := parseLayerBlock(block)
+		if layer.Name == "walls" {
+			for _, row := range layer.Tiles {
+				for _, gid := range row {
+					if gid > 0 {
+						tm.WallGID[gid] = true
+					}
+				}
+			}
+		}
+		tm.Layers = append(tm.Layers, layer)
+	}
+	if len(tm.Layers) == 0 {
+		log.Fatal("TMX: no <layer> tags")
 	}
-	row := tm.Tiles[ty]
-	if tx < 0 || tx >= len(row) {
+
+	parseObjectGroups(text, tm)
+
+	return tm
+}
+
+// ----------------------------------------------------------------------
+// COLLISION
+// ----------------------------------------------------------------------
+//
+// isWallAtPixel checks a pixel-space AABB (typically the player's bounding
+// box) against every tile it overlaps, across every layer. Leaving the map
+// bounds counts as a wall too.
+func isWallAtPixel(tm *TileMap, x, y, w, h float64) bool {
+	if tm == nil || tm.Width <= 0 || tm.Height <= 0 {
 		return false
 	}
 
-	gid := row[tx]
+	left := int(x) / tileSize
+	top := int(y) / tileSize
+	right := int(x+w-1) / tileSize
+	bottom := int(y+h-1) / tileSize
 
-	// If this tile ID is in WallGID, it's solid.
-	if tm.WallGID[gid] {
-		return true
+	for ty := top; ty <= bottom; ty++ {
+		for tx := left; tx <= right; tx++ {
+			if tx < 0 || ty < 0 || tx >= tm.Width || ty >= tm.Height {
+				return true
+			}
+			if tm.isWallTile(tx, ty) {
+				return true
+			}
+		}
 	}
 	return false
 }
@@ -375,9 +761,25 @@ func isWallAtPixel(tm *TileMap, px, py float64) bool {
 // ----------------------------------------------------------------------
 //
 
-func spawnItems(tm *TileMap, bad bool, images []*ebiten.Image) []Item {
+// randomItemSpotTile picks a random floor tile inside one of the map's
+// ItemSpots (hand-placed on a TMX object layer, or one per room for a
+// procedural dungeon), falling back to any floor tile on the map when
+// it has none.
+func randomItemSpotTile(tm *TileMap) (int, int, bool) {
+	if len(tm.ItemSpots) == 0 {
+		x := rand.Intn(tm.Width)
+		y := rand.Intn(tm.Height)
+		return x, y, tm.isFloor(x, y)
+	}
+
+	spot := tm.ItemSpots[rand.Intn(len(tm.ItemSpots))]
+	x, y := randomTileInRect(spot)
+	return x, y, tm.isFloor(x, y)
+}
+
+func spawnItems(tm *TileMap, bad bool, images []*ebiten.Image, kinds []ItemKind) []Item {
 	items := []Item{}
-	if tm == nil || tm.Width <= 0 || tm.Height <= 0 || len(tm.Tiles) == 0 {
+	if tm == nil || tm.Width <= 0 || tm.Height <= 0 || len(tm.Layers) == 0 {
 		fmt.Println("WARNING: spawnItems called with empty map")
 		return items
 	}
@@ -397,28 +799,24 @@ func spawnItems(tm *TileMap, bad bool, images []*ebiten.Image) []Item {
 	for len(items) < target && tries < maxTries {
 		tries++
 
-		x := rand.Intn(tm.Width)
-		y := rand.Intn(len(tm.Tiles))
-
-		row := tm.Tiles[y]
-		if x < 0 || x >= len(row) {
+		x, y, ok := randomItemSpotTile(tm)
+		if !ok {
 			continue
 		}
 
-		gid := row[x]
-		// If we ever mark walls, skip them. Right now WallGID is empty.
-		if tm.WallGID[gid] {
-			continue
+		idx := rand.Intn(len(images))
+		kind := ItemKindPotion
+		if idx < len(kinds) {
+			kind = kinds[idx]
 		}
 
-		img := images[rand.Intn(len(images))]
-
 		items = append(items, Item{
 			X:      float64(x * tileSize),
 			Y:      float64(y * tileSize),
 			Bad:    bad,
 			Active: true,
-			Img:    img,
+			Img:    images[idx],
+			Kind:   kind,
 		})
 	}
 
@@ -429,43 +827,35 @@ func spawnItems(tm *TileMap, bad bool, images []*ebiten.Image) []Item {
 	return items
 }
 
-//
-// ----------------------------------------------------------------------
-// PLAYER SPRITES
-// ----------------------------------------------------------------------
-//
-
-// We only have one knight frame, so we just use it for all directions.
-func loadPlayerFrames() map[string][]*ebiten.Image {
-	img := loadSprite(playerKnightPNG)
-
-	return map[string][]*ebiten.Image{
-		"down":  {img},
-		"up":    {img},
-		"left":  {img},
-		"right": {img},
-	}
-}
-
 //
 // ----------------------------------------------------------------------
 // GAME CONSTRUCTION
 // ----------------------------------------------------------------------
 //
 
-func newGame() *Game {
+func newGame(procgen bool) *Game {
 	fmt.Println(">>> newGame() start")
 
 	g := &Game{
 		levelGoal: itemsNeeded,
 	}
+	g.cam.Zoom = 1.0
+	g.camScaleTo = 1.0
 
 	g.tileImages = loadTilesheet()
-	g.map1 = parseTMX(level1TMX)
-	g.map2 = parseTMX(level2TMX)
+	if procgen {
+		g.map1 = generateDungeon(mapWidth, mapHeight)
+		g.map2 = generateDungeon(mapWidth, mapHeight)
+	} else {
+		g.map1 = parseTMX(level1TMX)
+		g.map2 = parseTMX(level2TMX)
+	}
 
-	fmt.Printf("map1 size: %d x %d tiles rows: %d\n", g.map1.Width, g.map1.Height, len(g.map1.Tiles))
-	fmt.Printf("map2 size: %d x %d tiles rows: %d\n", g.map2.Width, g.map2.Height, len(g.map2.Tiles))
+	fmt.Printf("map1 size: %d x %d layers: %d\n", g.map1.Width, g.map1.Height, len(g.map1.Layers))
+	fmt.Printf("map2 size: %d x %d layers: %d\n", g.map2.Width, g.map2.Height, len(g.map2.Layers))
+
+	// shared atlas for every simple (non-animated) sprite
+	g.imageAtlas = loadImageAtlas()
 
 	// player
 	g.player = Player{
@@ -475,20 +865,47 @@ func newGame() *Game {
 	}
 
 	// items
-	g.goodItemImages = loadSprites(flaskRedPNG, flaskBluePNG, flaskYellowPNG)
-	g.badItemImages = loadSprites(weaponBombPNG, weaponChopperPNG, weaponDaggerSilverPNG, weaponDaggerSmallPNG)
+	g.goodItemImages = []*ebiten.Image{
+		g.imageAtlas[ImgFlaskRed],
+		g.imageAtlas[ImgFlaskBlue],
+		g.imageAtlas[ImgFlaskYellow],
+		g.imageAtlas[ImgFlaskGreen],
+	}
+	g.goodItemKinds = []ItemKind{ItemKindPotion, ItemKindPotion, ItemKindRepel, ItemKindTorch} // yellow flask repels NPCs, green flask boosts torch light
+	g.badItemImages = []*ebiten.Image{
+		g.imageAtlas[ImgWeaponBomb],
+		g.imageAtlas[ImgWeaponChopper],
+		g.imageAtlas[ImgWeaponDaggerSilver],
+		g.imageAtlas[ImgWeaponDaggerSmall],
+	}
+	g.badItemKinds = []ItemKind{
+		ItemKindWeaponBomb,
+		ItemKindWeaponChopper,
+		ItemKindWeaponDaggerSilver,
+		ItemKindWeaponDaggerSmall,
+	}
+	g.weaponDefs = newWeaponDefs(g.badItemImages)
+
+	// sound effects
+	g.sound = newSoundPlayer()
+
+	// lighting / fog of war
+	g.lightImage = ebiten.NewImage(viewportWidth, viewportHeight)
+	g.lightSprite = newLightSprite()
+	g.minLevelColorScale = 0.12
+	g.minPlayerColorScale = 0.45
 
 	// door + NPCs
-	g.doorImage = loadSprite(doorClosedPNG)
-	g.npcImages = loadSprites(
-		npcMerchantPNG,
-		npcMerchant2PNG,
-		npcPaladinPNG,
-		npcSagePNG,
-		npcTricksterPNG,
-		npcWizzardPNG,
-		npcElf2PNG,
-	)
+	g.doorImage = g.imageAtlas[ImgDoorClosed]
+	g.npcFrameSets = [][]*ebiten.Image{
+		loadNPCFrames(npcMerchantPNG),
+		loadNPCFrames(npcMerchant2PNG),
+		loadNPCFrames(npcPaladinPNG),
+		loadNPCFrames(npcSagePNG),
+		loadNPCFrames(npcTricksterPNG),
+		loadNPCFrames(npcWizzardPNG),
+		loadNPCFrames(npcElf2PNG),
+	}
 
 	g.setupLevel(1)
 
@@ -510,22 +927,51 @@ func (g *Game) setupLevel(level int) {
 	g.msg = ""
 	g.doorVisible = false
 	g.npcs = nil
+	g.gibs = nil
+	g.projectiles = nil
 
 	tm := g.currentMap()
 	if tm == nil {
 		return
 	}
 
-	// spawn player somewhere safe-ish (2,2)
-	g.player.X = float64(2 * tileSize)
-	g.player.Y = float64(2 * tileSize)
+	g.worldTorches = make([]Light, 0, len(tm.TorchSpots))
+	for _, spot := range tm.TorchSpots {
+		g.worldTorches = append(g.worldTorches, Light{
+			X:      float64(spot.X*tileSize) + float64(spot.W*tileSize)/2,
+			Y:      float64(spot.Y*tileSize) + float64(spot.H*tileSize)/2,
+			Radius: lightWallTorchRadius,
+		})
+	}
+
+	// spawn the player at the TMX "spawn" object if the map has one,
+	// otherwise hunt for a random floor tile anywhere on the map, using the
+	// old safe-ish (2,2) corner only if none turns up
+	if tm.PlayerSpawn.W > 0 || tm.PlayerSpawn.H > 0 {
+		g.player.X = float64(tm.PlayerSpawn.X * tileSize)
+		g.player.Y = float64(tm.PlayerSpawn.Y * tileSize)
+	} else {
+		x, y := findFloorTile(tm, 2, 2)
+		g.player.X = float64(x * tileSize)
+		g.player.Y = float64(y * tileSize)
+	}
 	g.player.Dir = "down"
 	g.player.Frame = 0
 	g.player.FrameTick = 0
+	g.player.RepelTicks = 0
+	g.player.TorchTicks = 0
+	g.player.Weapon = ItemKindNone
+	g.player.Ammo = 0
+
+	// snap the camera onto the new player position instead of panning
+	// across the old level's map first
+	viewW, viewH := g.viewSize()
+	g.cam.X = g.player.X + float64(tileSize)/2 - viewW/2
+	g.cam.Y = g.player.Y + float64(tileSize)/2 - viewH/2
 
 	// spawn items
-	g.goodItems = spawnItems(tm, false, g.goodItemImages)
-	g.badItems = spawnItems(tm, true, g.badItemImages)
+	g.goodItems = spawnItems(tm, false, g.goodItemImages, g.goodItemKinds)
+	g.badItems = spawnItems(tm, true, g.badItemImages, g.badItemKinds)
 
 	// level 2 gets NPCs
 	if level == 2 {
@@ -533,43 +979,80 @@ func (g *Game) setupLevel(level int) {
 	}
 }
 
-// create 7 NPCs walking in different patterns
+// create 7 NPCs, patrolling the map's NPCPatrols rects when the current
+// map has any (hand-placed on a TMX object layer), otherwise falling
+// back to random floor tiles anywhere on the map (the map center only if
+// none turns up). Finding floor tiles
+// rather than using fixed coordinates keeps the fallback working on
+// procedurally generated dungeons, not just the hand-authored level 2
+// TMX.
 func (g *Game) setupNPCs() {
 	tm := g.currentMap()
-	if tm == nil || len(g.npcImages) == 0 {
+	if tm == nil || len(g.npcFrameSets) == 0 {
 		return
 	}
 
 	g.npcs = nil
 
-	tile := func(tx, ty int) (float64, float64) {
-		return float64(tx * tileSize), float64(ty * tileSize)
-	}
+	const npcCount = 7
+	const patrolRadius = 4
+
+	for i := 0; i < npcCount; i++ {
+		var tx, ty, minTx, maxTx, minTy, maxTy int
+
+		if len(tm.NPCPatrols) > 0 {
+			patrol := tm.NPCPatrols[i%len(tm.NPCPatrols)]
+			if patrol.W > 1 || patrol.H > 1 {
+				// a drawn patrol box: wander anywhere inside it
+				tx, ty = randomTileInRect(patrol)
+				if !tm.isFloor(tx, ty) {
+					tx, ty = findFloorTile(tm, patrol.X, patrol.Y)
+				}
+				minTx, maxTx = patrol.X, patrol.X+patrol.W-1
+				minTy, maxTy = patrol.Y, patrol.Y+patrol.H-1
+			} else {
+				// a single-point patrol marker: patrol a radius around it
+				tx, ty = patrol.X, patrol.Y
+				fx, fy := patrolPartner(tm, tx, ty, patrolRadius)
+				minTx, maxTx = minMax(tx, fx)
+				minTy, maxTy = minMax(ty, fy)
+			}
+		} else {
+			tx, ty = findFloorTile(tm, tm.Width/2, tm.Height/2)
+
+			// patrol between the anchor tile and a second floor tile
+			// within patrolRadius tiles of it
+			fx, fy := patrolPartner(tm, tx, ty, patrolRadius)
+			minTx, maxTx = minMax(tx, fx)
+			minTy, maxTy = minMax(ty, fy)
+		}
+
+		vx, vy := 1.0, 0.0
+		if i%2 == 1 {
+			vx, vy = 0.0, 1.0
+		}
 
-	add := func(idx, tx, ty int, vx, vy float64, minTx, maxTx, minTy, maxTy int) {
-		img := g.npcImages[idx%len(g.npcImages)]
-		x, y := tile(tx, ty)
 		g.npcs = append(g.npcs, NPC{
-			X:    x,
-			Y:    y,
+			X:    float64(tx * tileSize),
+			Y:    float64(ty * tileSize),
 			VX:   vx,
 			VY:   vy,
 			MinX: float64(minTx * tileSize),
 			MaxX: float64(maxTx * tileSize),
 			MinY: float64(minTy * tileSize),
 			MaxY: float64(maxTy * tileSize),
-			Img:  img,
+
+			Behavior:   BehaviorPatrol,
+			MinSpeed:   0.6,
+			MaxSpeed:   1.6,
+			Speed:      vx + vy, // matches the initial VX/VY above
+			nextAction: rand.Intn(288),
+
+			Health: npcDefaultHealth,
+
+			Frames: g.npcFrameSets[i%len(g.npcFrameSets)],
 		})
 	}
-
-	// horizontal, vertical and diagonal motions
-	add(0, 5, 5, 1.0, 0.0, 4, 10, 5, 5)
-	add(1, 10, 8, -1.2, 0.0, 5, 12, 8, 8)
-	add(2, 7, 12, 0.0, 1.0, 7, 7, 10, 16)
-	add(3, 12, 6, 0.0, -1.1, 12, 12, 4, 14)
-	add(4, 3, 14, 0.8, 0.8, 2, 8, 13, 18)
-	add(5, 15, 10, -0.8, 0.8, 12, 18, 8, 16)
-	add(6, 9, 3, 0.0, 1.3, 9, 9, 2, 15)
 }
 
 //
@@ -607,15 +1090,20 @@ func (g *Game) updatePlayer() {
 	moving := dx != 0 || dy != 0
 
 	if moving {
+		// Collide with a box slightly smaller than a full tile so the
+		// player doesn't snag on wall corners while hugging a corridor.
+		const inset = 3
+		boxSize := float64(tileSize) - 2*inset
+
 		// X movement
 		newX := g.player.X + dx
-		if !isWallAtPixel(tm, newX+float64(tileSize)/2, g.player.Y+float64(tileSize)/2) {
+		if !isWallAtPixel(tm, newX+inset, g.player.Y+inset, boxSize, boxSize) {
 			g.player.X = newX
 		}
 
 		// Y movement
 		newY := g.player.Y + dy
-		if !isWallAtPixel(tm, g.player.X+float64(tileSize)/2, newY+float64(tileSize)/2) {
+		if !isWallAtPixel(tm, g.player.X+inset, newY+inset, boxSize, boxSize) {
 			g.player.Y = newY
 		}
 
@@ -649,22 +1137,31 @@ func (g *Game) updateItems() {
 		if dist2(px, py, it.X+float64(tileSize)/2, it.Y+float64(tileSize)/2) < r2 {
 			it.Active = false
 			g.collected++
+			if it.Kind == ItemKindRepel {
+				g.player.RepelTicks = repelDurationTicks
+			}
+			if it.Kind == ItemKindTorch {
+				g.player.TorchTicks = torchBoostDurationTicks
+			}
+			g.sound.Play(sfxPickup)
 			if g.collected >= g.levelGoal && !g.doorVisible {
 				g.spawnDoor()
 			}
 		}
 	}
 
-	// bad items
+	// bad items are weapon pickups: grab one to arm (or re-arm and
+	// top off ammo for) that weapon
 	for i := range g.badItems {
 		it := &g.badItems[i]
 		if !it.Active {
 			continue
 		}
 		if dist2(px, py, it.X+float64(tileSize)/2, it.Y+float64(tileSize)/2) < r2 {
-			g.gameOver = true
-			g.msg = "You hit a bad item! Press SPACE to restart."
-			return
+			it.Active = false
+			g.player.Weapon = it.Kind
+			g.player.Ammo += weaponAmmoPerPick
+			g.sound.Play(sfxPickup)
 		}
 	}
 }
@@ -674,11 +1171,17 @@ func (g *Game) spawnDoor() {
 	if tm == nil {
 		return
 	}
-	// simple: bottom-right corner
-	x := tm.Width - 2
-	y := tm.Height - 2
-	g.doorX = float64(x * tileSize)
-	g.doorY = float64(y * tileSize)
+	// prefer the TMX "door" object; otherwise hunt for a random floor tile
+	// anywhere on the map, using the old bottom-right corner only if none
+	// turns up
+	if tm.DoorSpawn.W > 0 || tm.DoorSpawn.H > 0 {
+		g.doorX = float64(tm.DoorSpawn.X * tileSize)
+		g.doorY = float64(tm.DoorSpawn.Y * tileSize)
+	} else {
+		x, y := findFloorTile(tm, tm.Width-2, tm.Height-2)
+		g.doorX = float64(x * tileSize)
+		g.doorY = float64(y * tileSize)
+	}
 	g.doorVisible = true
 }
 
@@ -702,29 +1205,117 @@ func (g *Game) updateDoor() {
 	}
 }
 
+const (
+	npcSeekDistanceTiles = 6    // how close the player has to be to trigger Seek/Flee
+	npcRandomSeekChance  = 0.05 // small chance to Seek even with the player far away
+)
+
+// updateNPCs steps each NPC's current behavior, re-planning it on its own
+// countdown (or immediately, if the next step would walk it into a wall).
 func (g *Game) updateNPCs() {
+	tm := g.currentMap()
+	px := g.player.X + float64(tileSize)/2
+	py := g.player.Y + float64(tileSize)/2
+	playerIsRepelling := g.player.RepelTicks > 0
+
 	for i := range g.npcs {
 		npc := &g.npcs[i]
 
-		npc.X += npc.VX
-		npc.Y += npc.VY
+		npc.nextAction--
+		if npc.nextAction <= 0 {
+			g.planNPCMove(npc, px, py, playerIsRepelling)
+		}
+
+		newX := npc.X + npc.VX
+		newY := npc.Y + npc.VY
+
+		if tm != nil && !tm.isFloor(int(newX)/tileSize, int(newY)/tileSize) {
+			// wall ahead: cancel this move and re-plan right away
+			npc.VX, npc.VY = 0, 0
+			npc.nextAction = 0
+			continue
+		}
+
+		if npc.Behavior == BehaviorPatrol {
+			// keep the old ping-pong-in-a-box feel for patrolling NPCs
+			if newX < npc.MinX || newX > npc.MaxX {
+				npc.VX = -npc.VX
+				newX = npc.X + npc.VX
+			}
+			if newY < npc.MinY || newY > npc.MaxY {
+				npc.VY = -npc.VY
+				newY = npc.Y + npc.VY
+			}
+		}
+
+		npc.X = newX
+		npc.Y = newY
 
-		if npc.X < npc.MinX {
-			npc.X = npc.MinX
-			npc.VX = -npc.VX
-		} else if npc.X > npc.MaxX {
-			npc.X = npc.MaxX
-			npc.VX = -npc.VX
+		if npc.VX != 0 || npc.VY != 0 {
+			npc.FrameTick++
+			if npc.FrameTick >= 8 {
+				npc.FrameTick = 0
+				if len(npc.Frames) > 0 {
+					npc.Frame = (npc.Frame + 1) % len(npc.Frames)
+				}
+			}
+		} else {
+			npc.FrameTick = 0
+			npc.Frame = 0
 		}
+	}
+}
+
+// planNPCMove decides what an NPC does next: flee the player if they're
+// holding an active repel item and nearby, seek them if they're close (or
+// on a small random whim), otherwise fall back to patrol/wander.
+func (g *Game) planNPCMove(npc *NPC, px, py float64, playerIsRepelling bool) {
+	centerX := npc.X + float64(tileSize)/2
+	centerY := npc.Y + float64(tileSize)/2
+	dx := px - centerX
+	dy := py - centerY
+	distTiles := math.Hypot(dx, dy) / tileSize
+
+	switch {
+	case playerIsRepelling && distTiles <= npcSeekDistanceTiles:
+		npc.Behavior = BehaviorFlee
+	case distTiles <= npcSeekDistanceTiles || rand.Float64() < npcRandomSeekChance:
+		npc.Behavior = BehaviorSeek
+	case rand.Float64() < 0.3:
+		npc.Behavior = BehaviorWander
+	default:
+		npc.Behavior = BehaviorPatrol
+	}
 
-		if npc.Y < npc.MinY {
-			npc.Y = npc.MinY
-			npc.VY = -npc.VY
-		} else if npc.Y > npc.MaxY {
-			npc.Y = npc.MaxY
-			npc.VY = -npc.VY
+	npc.Speed = npc.MinSpeed + rand.Float64()*(npc.MaxSpeed-npc.MinSpeed)
+
+	switch npc.Behavior {
+	case BehaviorSeek:
+		a := math.Atan2(dy, dx)
+		npc.VX = math.Cos(a) * npc.Speed
+		npc.VY = math.Sin(a) * npc.Speed
+	case BehaviorFlee:
+		a := math.Atan2(-dy, -dx)
+		npc.VX = math.Cos(a) * npc.Speed
+		npc.VY = math.Sin(a) * npc.Speed
+	case BehaviorWander:
+		a := rand.Float64() * 2 * math.Pi
+		npc.VX = math.Cos(a) * npc.Speed
+		npc.VY = math.Sin(a) * npc.Speed
+	default: // Patrol: keep moving toward whichever box edge it was already headed for
+		if npc.VX == 0 && npc.VY == 0 {
+			// a wall bump (or the very first plan) zeroed both axes: pick
+			// back up along the box's long axis instead of always East,
+			// so vertical patrollers don't turn into horizontal ones
+			if npc.MaxX-npc.MinX >= npc.MaxY-npc.MinY {
+				npc.VX = npc.Speed
+			} else {
+				npc.VY = npc.Speed
+			}
 		}
 	}
+
+	npc.nextAction = 288 + rand.Intn(720)
 }
 
 //
@@ -753,12 +1344,20 @@ func (g *Game) Update() error {
 		g.setupLevel(g.level)
 	}
 
+	if g.player.RepelTicks > 0 {
+		g.player.RepelTicks--
+	}
+
 	g.updatePlayer()
 	g.updateItems()
 	g.updateDoor()
+	g.updateCombat()
+	g.updateProjectiles()
 	if g.level == 2 {
 		g.updateNPCs()
 	}
+	g.updateCamera()
+	g.updateLighting()
 
 	return nil
 }
@@ -769,55 +1368,82 @@ func (g *Game) drawMap(screen *ebiten.Image) {
 		return
 	}
 
-	for y, row := range tm.Tiles {
-		for x, gid := range row {
-			if gid <= 0 {
-				continue
-			}
-			idx := gid - 1
-			if idx < 0 || idx >= len(g.tileImages) {
-				continue
+	// only walk the tile rows/columns that can actually land on screen
+	viewW, viewH := g.viewSize()
+	minTX := int(g.cam.X) / tileSize
+	minTY := int(g.cam.Y) / tileSize
+	maxTX := int(g.cam.X+viewW)/tileSize + 1
+	maxTY := int(g.cam.Y+viewH)/tileSize + 1
+	if minTX < 0 {
+		minTX = 0
+	}
+	if minTY < 0 {
+		minTY = 0
+	}
+
+	// draw each layer in TMX order, so "decor" lands on top of "walls"
+	// which lands on top of "floor"
+	for _, layer := range tm.Layers {
+		for y := minTY; y <= maxTY && y < len(layer.Tiles); y++ {
+			row := layer.Tiles[y]
+			for x := minTX; x <= maxTX && x < len(row); x++ {
+				gid := row[x]
+				if gid <= 0 {
+					continue
+				}
+				idx := gid - 1
+				if idx < 0 || idx >= len(g.tileImages) {
+					continue
+				}
+				op := &ebiten.DrawImageOptions{}
+				op.GeoM.Translate(float64(x*tileSize), float64(y*tileSize))
+				g.worldToScreen(op)
+				screen.DrawImage(g.tileImages[idx], op)
 			}
-			op := &ebiten.DrawImageOptions{}
-			op.GeoM.Translate(float64(x*tileSize), float64(y*tileSize))
-			screen.DrawImage(g.tileImages[idx], op)
 		}
 	}
 }
 
 func (g *Game) drawItems(screen *ebiten.Image) {
 	for _, it := range g.goodItems {
-		if !it.Active {
+		if !it.Active || !g.onScreen(it.X, it.Y, tileSize, tileSize) {
 			continue
 		}
 		op := &ebiten.DrawImageOptions{}
 		op.GeoM.Translate(it.X, it.Y)
+		g.worldToScreen(op)
 		screen.DrawImage(it.Img, op)
 	}
 	for _, it := range g.badItems {
-		if !it.Active {
+		if !it.Active || !g.onScreen(it.X, it.Y, tileSize, tileSize) {
 			continue
 		}
 		op := &ebiten.DrawImageOptions{}
 		op.GeoM.Translate(it.X, it.Y)
+		g.worldToScreen(op)
 		screen.DrawImage(it.Img, op)
 	}
 }
 
 func (g *Game) drawNPCs(screen *ebiten.Image) {
 	for _, npc := range g.npcs {
+		if len(npc.Frames) == 0 || !g.onScreen(npc.X, npc.Y, tileSize, tileSize) {
+			continue
+		}
 		op := &ebiten.DrawImageOptions{}
 		op.GeoM.Translate(npc.X, npc.Y)
-		screen.DrawImage(npc.Img, op)
+		g.worldToScreen(op)
+		screen.DrawImage(npc.Frames[npc.Frame%len(npc.Frames)], op)
 	}
 }
 
 func (g *Game) drawDoor(screen *ebiten.Image) {
-	if !g.doorVisible || g.doorImage == nil {
+	if !g.doorVisible || g.doorImage == nil || !g.onScreen(g.doorX, g.doorY, tileSize, tileSize) {
 		return
 	}
 	op := &ebiten.DrawImageOptions{}
 	op.GeoM.Translate(g.doorX, g.doorY)
+	g.worldToScreen(op)
 	screen.DrawImage(g.doorImage, op)
 }
 
@@ -825,7 +1451,9 @@ func (g *Game) Draw(screen *ebiten.Image) {
 	g.drawMap(screen)
 	g.drawItems(screen)
 	g.drawDoor(screen)
+	g.drawGibs(screen)
 	g.drawNPCs(screen)
+	g.drawProjectiles(screen)
 
 	// draw player last so they appear on top
 	frames := g.player.Frames[g.player.Dir]
@@ -835,10 +1463,13 @@ func (g *Game) Draw(screen *ebiten.Image) {
 	}
 	op := &ebiten.DrawImageOptions{}
 	op.GeoM.Translate(g.player.X, g.player.Y)
+	g.worldToScreen(op)
 	screen.DrawImage(img, op)
 
+	g.drawLighting(screen)
+
 	// simple HUD at top-left
-	hud := fmt.Sprintf("Level: %d   Items: %d / %d", g.level, g.collected, g.levelGoal)
+	hud := fmt.Sprintf("Level: %d   Items: %d / %d   Ammo: %d", g.level, g.collected, g.levelGoal, g.player.Ammo)
 	if g.msg != "" {
 		hud += "\n" + g.msg
 	}
@@ -846,7 +1477,7 @@ func (g *Game) Draw(screen *ebiten.Image) {
 }
 
 func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
-	return screenWidth, screenHeight
+	return viewportWidth, viewportHeight
 }
 
 //
@@ -859,12 +1490,16 @@ func main() {
 	log.SetFlags(0)
 	fmt.Println(">>> main() starting")
 
+	procgen := flag.Bool("procgen", false, "generate dungeon levels procedurally instead of loading the bundled TMX maps")
+	flag.Parse()
+
 	rand.Seed(time.Now().UnixNano())
 
-	game := newGame()
+	game := newGame(*procgen)
 
-	// window is same as logical size, so tiles fill the whole thing
-	ebiten.SetWindowSize(screenWidth, screenHeight)
+	// window is the fixed viewport; the camera (camera.go) handles maps
+	// bigger or smaller than it
+	ebiten.SetWindowSize(viewportWidth, viewportHeight)
 	ebiten.SetWindowTitle("Project 2 - Olijah Williams")
 
 	if err := ebiten.RunGame(game); err != nil {
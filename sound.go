@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+	"github.com/hajimehoshi/ebiten/v2/audio/wav"
+)
+
+//
+// ----------------------------------------------------------------------
+// SOUND EFFECTS
+// ----------------------------------------------------------------------
+//
+
+//go:embed assets/sfx/shot.wav
+var shotWAV []byte
+
+//go:embed assets/sfx/hit.wav
+var hitWAV []byte
+
+//go:embed assets/sfx/pickup.wav
+var pickupWAV []byte
+
+const (
+	sfxShot   = "shot"
+	sfxHit    = "hit"
+	sfxPickup = "pickup"
+)
+
+const sampleRate = 44100
+
+// SoundPlayer decodes a handful of embedded .wav clips once and replays
+// them by name through a single shared audio context.
+type SoundPlayer struct {
+	ctx     *audio.Context
+	players map[string]*audio.Player
+}
+
+// newSoundPlayer loads every known sound effect. A clip that fails to
+// decode is simply skipped (Play becomes a no-op for it) rather than
+// crashing the game over a missing sound.
+func newSoundPlayer() *SoundPlayer {
+	sp := &SoundPlayer{
+		ctx:     audio.NewContext(sampleRate),
+		players: make(map[string]*audio.Player),
+	}
+
+	sp.load(sfxShot, shotWAV)
+	sp.load(sfxHit, hitWAV)
+	sp.load(sfxPickup, pickupWAV)
+
+	return sp
+}
+
+func (sp *SoundPlayer) load(name string, wavBytes []byte) {
+	stream, err := wav.DecodeWithoutResampling(bytes.NewReader(wavBytes))
+	if err != nil {
+		log.Printf("sound: failed to decode %q: %v", name, err)
+		return
+	}
+	player, err := sp.ctx.NewPlayer(stream)
+	if err != nil {
+		log.Printf("sound: failed to create player for %q: %v", name, err)
+		return
+	}
+	sp.players[name] = player
+}
+
+// Play restarts and plays the named clip. Unknown or failed-to-load names
+// are ignored.
+func (sp *SoundPlayer) Play(name string) {
+	player, ok := sp.players[name]
+	if !ok {
+		return
+	}
+	player.Rewind()
+	player.Play()
+}
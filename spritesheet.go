@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+//
+// ----------------------------------------------------------------------
+// SPRITESHEET ANIMATION
+// ----------------------------------------------------------------------
+//
+// SpriteSheet decodes one embedded PNG once and slices out named
+// sub-images by tile coordinates, so an animated actor's whole walk cycle
+// shares a single GPU texture instead of one ebiten.Image per frame. Our
+// bundled sprites are still plain single-frame PNGs, so frame/row clamp
+// to whatever rows/columns the image actually has instead of assuming a
+// walk-cycle layout that isn't there — on a single-frame PNG that just
+// means every requested frame/direction resolves to that one frame.
+//
+
+const (
+	playerFrameSize    = 16
+	playerFramesPerDir = 4
+
+	npcFrameSize  = 16
+	npcFrameCount = 4
+)
+
+// SpriteSheet is a decoded spritesheet PNG, ready to be cut into
+// frameSize-square frames. cols/rows are however many whole frames
+// actually fit in the decoded image, not an assumed layout.
+type SpriteSheet struct {
+	img  *ebiten.Image
+	cols int
+	rows int
+}
+
+func newSpriteSheet(pngBytes []byte, frameSize int) *SpriteSheet {
+	decoded, _, err := image.Decode(bytes.NewReader(pngBytes))
+	if err != nil {
+		log.Fatalf("failed to decode spritesheet: %v", err)
+	}
+	img := ebiten.NewImageFromImage(decoded)
+	b := img.Bounds()
+
+	cols := b.Dx() / frameSize
+	if cols < 1 {
+		cols = 1
+	}
+	rows := b.Dy() / frameSize
+	if rows < 1 {
+		rows = 1
+	}
+
+	return &SpriteSheet{img: img, cols: cols, rows: rows}
+}
+
+// frame returns the frameSize-square sub-image at tile (col, row),
+// clamped to the sheet's actual bounds so a plain single-frame PNG just
+// returns that same frame for every (col, row) asked of it.
+func (ss *SpriteSheet) frame(col, row, frameSize int) *ebiten.Image {
+	if col >= ss.cols {
+		col = ss.cols - 1
+	}
+	if row >= ss.rows {
+		row = ss.rows - 1
+	}
+	x := col * frameSize
+	y := row * frameSize
+	return ss.img.SubImage(image.Rect(x, y, x+frameSize, y+frameSize)).(*ebiten.Image)
+}
+
+// row returns `count` consecutive frames starting at (0, row).
+func (ss *SpriteSheet) row(row, frameSize, count int) []*ebiten.Image {
+	frames := make([]*ebiten.Image, count)
+	for i := 0; i < count; i++ {
+		frames[i] = ss.frame(i, row, frameSize)
+	}
+	return frames
+}
+
+// KnightSheet exposes the player knight's walk cycle by direction. Rows
+// follow the tileset's down/up/left/right order.
+type KnightSheet struct {
+	WalkDown  []*ebiten.Image
+	WalkUp    []*ebiten.Image
+	WalkLeft  []*ebiten.Image
+	WalkRight []*ebiten.Image
+}
+
+func loadKnightSheet(pngBytes []byte) KnightSheet {
+	ss := newSpriteSheet(pngBytes, playerFrameSize)
+	return KnightSheet{
+		WalkDown:  ss.row(0, playerFrameSize, playerFramesPerDir),
+		WalkUp:    ss.row(1, playerFrameSize, playerFramesPerDir),
+		WalkLeft:  ss.row(2, playerFrameSize, playerFramesPerDir),
+		WalkRight: ss.row(3, playerFrameSize, playerFramesPerDir),
+	}
+}
+
+// loadPlayerFrames builds the player's per-direction walk cycles from the
+// knight spritesheet.
+func loadPlayerFrames() map[string][]*ebiten.Image {
+	knightSS := loadKnightSheet(playerKnightPNG)
+	return map[string][]*ebiten.Image{
+		"down":  knightSS.WalkDown,
+		"up":    knightSS.WalkUp,
+		"left":  knightSS.WalkLeft,
+		"right": knightSS.WalkRight,
+	}
+}
+
+// loadNPCFrames reads an NPC's own walk cycle off the first row of its
+// spritesheet. Frames clamp like every SpriteSheet lookup, so an NPC
+// whose PNG holds a single frame keeps that one pose.
+func loadNPCFrames(pngBytes []byte) []*ebiten.Image {
+	ss := newSpriteSheet(pngBytes, npcFrameSize)
+	return ss.row(0, npcFrameSize, npcFrameCount)
+}